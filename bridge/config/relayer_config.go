@@ -0,0 +1,23 @@
+package config
+
+import (
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+// RelayerConfig holds the layer2 relayer's on-chain and retry settings.
+// Only the fields the relayer package currently reads are defined here; the
+// rest of the relayer's configuration lives outside this diff.
+type RelayerConfig struct {
+	// RollupContractAddress is the layer1 rollup contract commitBatch/
+	// finalizeBatch transactions are sent to.
+	RollupContractAddress common.Address `json:"rollup_contract_address"`
+
+	// CommitRetryInterval is the base backoff between commitBatch retries
+	// for a given batch after a transient send failure.
+	CommitRetryInterval time.Duration `json:"commit_retry_interval"`
+	// CommitRetryMaxBackoff caps the exponential backoff applied to
+	// repeated commitBatch failures for the same batch.
+	CommitRetryMaxBackoff time.Duration `json:"commit_retry_max_backoff"`
+}
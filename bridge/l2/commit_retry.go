@@ -0,0 +1,90 @@
+package l2
+
+import (
+	"sync"
+	"time"
+)
+
+// commitRetryState tracks the backoff state for a single batch's commitBatch
+// tx, so ProcessPendingBatches doesn't hammer the sender with a retry every
+// tick after a transient failure like sender.ErrNoAvailableAccount.
+type commitRetryState struct {
+	attempts     int
+	nextEligible time.Time
+}
+
+// commitRetryTracker keeps per-batch commitRetryState in memory, keyed by
+// batch id, applying an exponential backoff capped at maxBackoff.
+type commitRetryTracker struct {
+	mu         sync.Mutex
+	interval   time.Duration
+	maxBackoff time.Duration
+	states     map[string]*commitRetryState
+
+	consecutiveNoAccount int
+}
+
+// newCommitRetryTracker creates a tracker using interval as the base backoff
+// and maxBackoff as the ceiling.
+func newCommitRetryTracker(interval, maxBackoff time.Duration) *commitRetryTracker {
+	return &commitRetryTracker{
+		interval:   interval,
+		maxBackoff: maxBackoff,
+		states:     make(map[string]*commitRetryState),
+	}
+}
+
+// Eligible reports whether id is due for another commit attempt.
+func (t *commitRetryTracker) Eligible(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.states[id]
+	if !ok {
+		return true
+	}
+	return !time.Now().Before(state.nextEligible)
+}
+
+// RecordFailure schedules id's next eligible attempt with exponential
+// backoff, capped at maxBackoff.
+func (t *commitRetryTracker) RecordFailure(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.states[id]
+	if !ok {
+		state = &commitRetryState{}
+		t.states[id] = state
+	}
+	state.attempts++
+
+	backoff := t.interval << uint(state.attempts-1) // nolint:gosec
+	if backoff <= 0 || backoff > t.maxBackoff {
+		backoff = t.maxBackoff
+	}
+	state.nextEligible = time.Now().Add(backoff)
+}
+
+// Clear drops id's retry state after a successful commit.
+func (t *commitRetryTracker) Clear(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.states, id)
+}
+
+// RecordNoAvailableAccount tracks consecutive sender.ErrNoAvailableAccount
+// failures across all batches, returning the new streak length so the
+// caller can alert once it crosses a threshold.
+func (t *commitRetryTracker) RecordNoAvailableAccount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.consecutiveNoAccount++
+	return t.consecutiveNoAccount
+}
+
+// ResetNoAvailableAccount clears the consecutive ErrNoAvailableAccount streak.
+func (t *commitRetryTracker) ResetNoAvailableAccount() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.consecutiveNoAccount = 0
+}
@@ -0,0 +1,53 @@
+package l2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitRetryTrackerEligibleByDefault(t *testing.T) {
+	tr := newCommitRetryTracker(time.Second, 10*time.Second)
+	assert.True(t, tr.Eligible("batch-a"))
+}
+
+func TestCommitRetryTrackerBackoffGrowsExponentiallyAndCaps(t *testing.T) {
+	tr := newCommitRetryTracker(time.Second, 5*time.Second)
+
+	tr.RecordFailure("batch-a")
+	assert.False(t, tr.Eligible("batch-a"))
+	first := tr.states["batch-a"].nextEligible
+
+	tr.RecordFailure("batch-a")
+	second := tr.states["batch-a"].nextEligible
+	assert.True(t, second.After(first), "backoff should grow after a second consecutive failure")
+
+	// Keep failing until the backoff would exceed maxBackoff; it should be
+	// capped there instead of overflowing or growing unbounded.
+	for i := 0; i < 10; i++ {
+		tr.RecordFailure("batch-a")
+	}
+	state := tr.states["batch-a"]
+	assert.LessOrEqual(t, time.Until(state.nextEligible), 5*time.Second+100*time.Millisecond)
+}
+
+func TestCommitRetryTrackerClearResetsState(t *testing.T) {
+	tr := newCommitRetryTracker(time.Second, 10*time.Second)
+
+	tr.RecordFailure("batch-a")
+	assert.False(t, tr.Eligible("batch-a"))
+
+	tr.Clear("batch-a")
+	assert.True(t, tr.Eligible("batch-a"))
+}
+
+func TestCommitRetryTrackerNoAvailableAccountStreak(t *testing.T) {
+	tr := newCommitRetryTracker(time.Second, 10*time.Second)
+
+	assert.Equal(t, 1, tr.RecordNoAvailableAccount())
+	assert.Equal(t, 2, tr.RecordNoAvailableAccount())
+
+	tr.ResetNoAvailableAccount()
+	assert.Equal(t, 1, tr.RecordNoAvailableAccount())
+}
@@ -0,0 +1,60 @@
+package l2
+
+import (
+	"context"
+	"sync"
+
+	"scroll-tech/bridge/config"
+	"scroll-tech/bridge/sender"
+
+	"scroll-tech/common/shutdown"
+
+	"scroll-tech/database"
+)
+
+// Layer2Relayer is responsible for watching layer 2 state, and submitting
+// the layer 2 batch data to layer 1 rollup contract. This file only carries
+// the fields and constructor needed by the commit-retry and graceful
+// shutdown logic in relayer_commit.go; the rest of the relayer (finalize,
+// message relaying, etc.) lives elsewhere.
+type Layer2Relayer struct {
+	ctx context.Context
+	cfg config.RelayerConfig
+	db  database.OrmFactory
+
+	rollupSender *sender.Sender
+
+	processingCommitment sync.Map
+
+	// shutdownMu guards stopped together with registering an in-flight
+	// commit in inFlightCommits, so a ProcessPendingBatches call can't
+	// observe stopped == false and then register after Stop has already
+	// measured inFlightCommits as drained. See beginCommitAttempt.
+	shutdownMu      sync.Mutex
+	stopped         bool
+	inFlightCommits sync.WaitGroup
+
+	commitRetry *commitRetryTracker
+}
+
+// NewLayer2Relayer creates a new Layer2Relayer, reconciling batches left
+// mid-commit from a previous run via commitInit.
+func NewLayer2Relayer(ctx context.Context, db database.OrmFactory, rollupSender *sender.Sender, cfg config.RelayerConfig, lifecycle *shutdown.Lifecycle) (*Layer2Relayer, error) {
+	r := &Layer2Relayer{
+		ctx:          ctx,
+		cfg:          cfg,
+		db:           db,
+		rollupSender: rollupSender,
+		commitRetry:  newCommitRetryTracker(cfg.CommitRetryInterval, cfg.CommitRetryMaxBackoff),
+	}
+
+	if err := r.commitInit(); err != nil {
+		return nil, err
+	}
+
+	if lifecycle != nil {
+		lifecycle.Register("layer2_relayer", r)
+	}
+
+	return r, nil
+}
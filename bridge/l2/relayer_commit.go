@@ -1,6 +1,7 @@
 package l2
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"sync"
@@ -14,6 +15,56 @@ import (
 	"scroll-tech/database/orm"
 )
 
+// commitRetryNoAccountAlertThreshold is the number of consecutive
+// sender.ErrNoAvailableAccount failures across all batches before the
+// relayer surfaces an alert-worthy log line.
+const commitRetryNoAccountAlertThreshold = 5
+
+// Stop implements shutdown.Stopper. It stops ProcessPendingBatches from
+// picking up new batches and waits for in-flight commit transactions to
+// finish (or ctx's deadline to elapse). It does not write
+// processingCommitment anywhere; on restart commitInit rebuilds it from the
+// RollupCommitting batches and commit tx hashes stored in the database.
+func (r *Layer2Relayer) Stop(ctx context.Context) error {
+	r.shutdownMu.Lock()
+	r.stopped = true
+	r.shutdownMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.inFlightCommits.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warn("layer2 relayer commit shutdown deadline reached with commits still in flight")
+	}
+
+	return nil
+}
+
+// beginCommitAttempt registers an in-flight commit attempt, atomically with
+// respect to Stop: it returns false without registering if the relayer has
+// already been told to stop. This closes a TOCTOU race where a caller could
+// observe stopped == false, then Stop could run inFlightCommits.Wait()
+// against a still-zero counter and return, before the caller got around to
+// calling inFlightCommits.Add(1) and committing a transaction afterward.
+func (r *Layer2Relayer) beginCommitAttempt() bool {
+	r.shutdownMu.Lock()
+	defer r.shutdownMu.Unlock()
+	if r.stopped {
+		return false
+	}
+	r.inFlightCommits.Add(1)
+	return true
+}
+
+// commitInit reconciles RollupCommitting batches on startup (or restart),
+// loading each one's commit tx into the sender or re-broadcasting it. Only
+// the first 10 RollupCommitting batches are fetched, and a batch whose
+// lookup or send fails is logged and skipped.
 func (r *Layer2Relayer) commitInit() error {
 	ids, err := r.db.GetBatchesByRollupStatus(orm.RollupCommitting, 10)
 	if err != nil || len(ids) == 0 {
@@ -116,6 +167,12 @@ func (r *Layer2Relayer) committedPack(id string) (*orm.BlockBatch, []byte, error
 // ProcessPendingBatches submit batch data to layer 1 rollup contract
 func (r *Layer2Relayer) ProcessPendingBatches(wg *sync.WaitGroup) {
 	defer wg.Done()
+
+	if !r.beginCommitAttempt() {
+		return
+	}
+	defer r.inFlightCommits.Done()
+
 	// batches are sorted by batch index in increasing order
 	batchesInDB, err := r.db.GetPendingBatches(1)
 	if err != nil {
@@ -128,6 +185,10 @@ func (r *Layer2Relayer) ProcessPendingBatches(wg *sync.WaitGroup) {
 	id := batchesInDB[0]
 	// @todo add support to relay multiple batches
 
+	if !r.commitRetry.Eligible(id) {
+		return
+	}
+
 	batch, data, err := r.committedPack(id)
 	if err != nil {
 		return
@@ -137,12 +198,20 @@ func (r *Layer2Relayer) ProcessPendingBatches(wg *sync.WaitGroup) {
 	// add suffix `-commit` to avoid duplication with finalize tx in unit tests
 	hash, err := r.rollupSender.SendTransaction(txID, &r.cfg.RollupContractAddress, big.NewInt(0), data)
 	if err != nil {
-		if !errors.Is(err, sender.ErrNoAvailableAccount) {
+		r.commitRetry.RecordFailure(id)
+		if errors.Is(err, sender.ErrNoAvailableAccount) {
+			if streak := r.commitRetry.RecordNoAvailableAccount(); streak >= commitRetryNoAccountAlertThreshold {
+				log.Error("repeated ErrNoAvailableAccount committing batches to layer1, account pool may be exhausted", "id", id, "consecutive_failures", streak)
+			}
+		} else {
+			r.commitRetry.ResetNoAvailableAccount()
 			log.Error("Failed to send commitBatch tx to layer1 ", "id", id, "index", batch.Index, "err", err)
 		}
 		return
 	}
 	log.Info("commitBatch in layer1", "batch_id", id, "index", batch.Index, "hash", hash)
+	r.commitRetry.Clear(id)
+	r.commitRetry.ResetNoAvailableAccount()
 
 	// record and sync with db, @todo handle db error
 	err = r.db.UpdateCommitTxHashAndRollupStatus(r.ctx, id, hash.String(), orm.RollupCommitting)
@@ -150,4 +219,4 @@ func (r *Layer2Relayer) ProcessPendingBatches(wg *sync.WaitGroup) {
 		log.Error("UpdateCommitTxHashAndRollupStatus failed", "id", id, "index", batch.Index, "err", err)
 	}
 	r.processingCommitment.Store(txID, id)
-}
\ No newline at end of file
+}
@@ -0,0 +1,68 @@
+package l2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeginCommitAttemptRejectedAfterStop(t *testing.T) {
+	r := &Layer2Relayer{}
+	r.stopped = true
+
+	assert.False(t, r.beginCommitAttempt())
+}
+
+func TestBeginCommitAttemptRegistersBeforeStopObservesDrain(t *testing.T) {
+	r := &Layer2Relayer{}
+
+	assert.True(t, r.beginCommitAttempt())
+
+	// Stop must see this attempt as in-flight: it should block on
+	// inFlightCommits.Wait() instead of returning immediately, since the
+	// counter was incremented before stopped was ever read as false.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = r.Stop(context.Background())
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Stop returned while a registered commit attempt was still in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	r.inFlightCommits.Done()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after the in-flight commit finished")
+	}
+}
+
+func TestBeginCommitAttemptRaceAgainstStop(t *testing.T) {
+	// Repeated attempts, racing beginCommitAttempt against Stop: every
+	// attempt that returns true must be observed as in-flight by the Stop
+	// call racing it, i.e. it's safe to call Done() unconditionally for
+	// every true result without either a negative WaitGroup counter or a
+	// leaked Add.
+	for i := 0; i < 200; i++ {
+		r := &Layer2Relayer{}
+
+		stopDone := make(chan struct{})
+		go func() {
+			_ = r.Stop(context.Background())
+			close(stopDone)
+		}()
+
+		if r.beginCommitAttempt() {
+			r.inFlightCommits.Done()
+		}
+
+		<-stopDone
+	}
+}
@@ -0,0 +1,84 @@
+// Package shutdown provides a small Lifecycle manager that lets the
+// relayer, watcher and other long-running loops register for coordinated,
+// deadline-bounded shutdown instead of being killed mid-transaction.
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// Stopper is implemented by components that need to participate in a
+// graceful shutdown: stop accepting new work and let in-flight work finish
+// (or a deadline elapse) before returning.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Lifecycle coordinates graceful shutdown across a set of registered
+// components, such as the coordinator's BatchProverTask, the
+// Layer2Relayer's ProcessPendingBatches loop, and the BatchProposer.
+type Lifecycle struct {
+	mu       sync.Mutex
+	stoppers map[string]Stopper
+}
+
+// New creates an empty Lifecycle manager.
+func New() *Lifecycle {
+	return &Lifecycle{stoppers: make(map[string]Stopper)}
+}
+
+// Register adds a named component to be stopped when Stop is called.
+// Registering the same name twice replaces the previous registration.
+func (l *Lifecycle) Register(name string, stopper Stopper) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stoppers[name] = stopper
+}
+
+// Stop stops every registered component concurrently, waiting for each to
+// finish or for ctx's deadline to elapse, whichever comes first.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	stoppers := make(map[string]Stopper, len(l.stoppers))
+	for name, stopper := range l.stoppers {
+		stoppers[name] = stopper
+	}
+	l.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(stoppers))
+	for name, stopper := range stoppers {
+		wg.Add(1)
+		go func(name string, stopper Stopper) {
+			defer wg.Done()
+			if err := stopper.Stop(ctx); err != nil {
+				log.Error("component failed to stop cleanly", "component", name, "error", err)
+				errs <- fmt.Errorf("%s: %w", name, err)
+			}
+		}(name, stopper)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	close(errs)
+
+	var firstErr error
+	for err := range errs {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/shutdown"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
 	"scroll-tech/common/utils"
@@ -22,10 +23,18 @@ import (
 // BatchProverTask is prover task implement for batch proof
 type BatchProverTask struct {
 	BaseCollector
+
+	scheduler  *TaskScheduler
+	liveness   *ProverLivenessTracker
+	backend    ProofBackend
+	dispatcher *ProofServerDispatcher
+
+	cancelBackgroundLoops context.CancelFunc
 }
 
-// NewBatchProverTask new a batch collector
-func NewBatchProverTask(cfg *config.Config, db *gorm.DB) *BatchProverTask {
+// NewBatchProverTask new a batch collector. lifecycle may be nil, in which
+// case the collector still runs but isn't stopped gracefully on shutdown.
+func NewBatchProverTask(cfg *config.Config, db *gorm.DB, lifecycle *shutdown.Lifecycle) *BatchProverTask {
 	bp := &BatchProverTask{
 		BaseCollector: BaseCollector{
 			db:            db,
@@ -34,32 +43,108 @@ func NewBatchProverTask(cfg *config.Config, db *gorm.DB) *BatchProverTask {
 			batchOrm:      orm.NewBatch(db),
 			proverTaskOrm: orm.NewProverTask(db),
 		},
+		scheduler: NewTaskScheduler(db,
+			cfg.ProverManager.BatchCollectionTimeSec.Duration(),
+			cfg.ProverManager.ProofDeadline.Duration(),
+			cfg.ProverManager.MaxAttempts,
+			cfg.ProverManager.QueueDepth,
+		),
+		liveness: NewProverLivenessTracker(db,
+			cfg.ProverManager.ProverLivenessTimeout.Duration(),
+			cfg.ProverManager.BatchCollectionTimeSec.Duration(),
+		),
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	bp.cancelBackgroundLoops = cancel
+
+	if cfg.ProverManager.BatchProverBackend == config.ProofBackendProofServer {
+		bp.backend = NewProofServerBackend(cfg.ProverManager.ProofServerEndpoint)
+		bp.dispatcher = NewProofServerDispatcher(bp.backend, db, cfg.ProverManager.ProofServerPollInterval.Duration())
+		bp.dispatcher.Start(loopCtx)
+	} else {
+		bp.backend = NewPullBackend()
 	}
+
+	bp.scheduler.Start(loopCtx)
+	bp.liveness.Start(loopCtx)
+
+	if lifecycle != nil {
+		lifecycle.Register("batch_prover_task", bp)
+	}
+
 	return bp
 }
 
+// Stop implements shutdown.Stopper: it stops the scheduler, liveness
+// tracker and proof-server dispatcher background loops, so no new task gets
+// enqueued and outstanding proof-server jobs are no longer polled. Collect
+// and CollectBatch keep handing out and dispatching tasks that are already
+// in the scheduler's queue.
+func (bp *BatchProverTask) Stop(_ context.Context) error {
+	bp.cancelBackgroundLoops()
+	return nil
+}
+
+// Heartbeat exposes the liveness tracker's heartbeat handler for wiring up
+// to the coordinator's router.
+func (bp *BatchProverTask) Heartbeat(ctx *gin.Context) {
+	bp.liveness.Heartbeat(ctx)
+}
+
 // Collect load and send batch tasks
 func (bp *BatchProverTask) Collect(ctx *gin.Context) (*coordinatorType.ProverTaskSchema, error) {
-	batchTasks, err := bp.batchOrm.GetUnassignedBatches(ctx, 1)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get unassigned batch proving tasks, error:%w", err)
-	}
-
-	if len(batchTasks) == 0 {
+	batchHash, ok := bp.scheduler.Next()
+	if !ok {
 		return nil, nil
 	}
 
-	if len(batchTasks) != 1 {
-		return nil, fmt.Errorf("get unassigned batch proving task len not 1, batch tasks:%v", batchTasks)
+	log.Info("start batch proof generation session", "id", batchHash)
+
+	publicKey, publicKeyExist := ctx.Get(coordinatorType.PublicKey)
+	if !publicKeyExist {
+		bp.scheduler.Release(batchHash)
+		return nil, fmt.Errorf("get public key from contex failed")
 	}
 
-	batchTask := batchTasks[0]
-	log.Info("start batch proof generation session", "id", batchTask.Hash)
+	proverName, proverNameExist := ctx.Get(coordinatorType.ProverName)
+	if !proverNameExist {
+		bp.scheduler.Release(batchHash)
+		return nil, fmt.Errorf("get prover name from contex failed")
+	}
 
-	if !bp.checkAttemptsExceeded(batchTask.Hash, message.ProofTypeBatch) {
-		return nil, fmt.Errorf("the batch task id:%s check attempts have reach the maximum", batchTask.Hash)
+	var assigned bool
+	transErr := bp.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		assigned, err = bp.assignBatchTask(ctx, tx, batchHash, publicKey.(string), proverName.(string))
+		return err
+	})
+	if transErr != nil {
+		bp.scheduler.Release(batchHash)
+		return nil, transErr
+	}
+	bp.scheduler.Confirm(batchHash)
+	if !assigned {
+		return nil, fmt.Errorf("the batch task id:%s check attempts have reach the maximum", batchHash)
 	}
+	bp.liveness.RecordAssignment(publicKey.(string))
+
+	return bp.formatAndDispatch(ctx, batchHash, publicKey.(string))
+}
 
+// CollectBatch assigns up to n pending batch-proof tasks to the calling
+// prover, for provers that want to pull a queue depth in one round trip
+// instead of calling Collect repeatedly. It pulls hashes from the same
+// bp.scheduler queue Collect uses, rather than reading unassigned batches
+// from the DB directly, so the two RPCs can't both observe and assign the
+// same batch before either's transaction commits. All of the hashes pulled
+// for this request are assigned in a single shared transaction, so a real
+// assignment failure partway through rolls back every row in the batch
+// instead of leaking the rows committed earlier in the loop. A task whose
+// attempts are exhausted doesn't fail the transaction: it's marked
+// permanently failed in place, within the same transaction, and skipped
+// while the rest proceed.
+func (bp *BatchProverTask) CollectBatch(ctx *gin.Context, n int) ([]*coordinatorType.ProverTaskSchema, error) {
 	publicKey, publicKeyExist := ctx.Get(coordinatorType.PublicKey)
 	if !publicKeyExist {
 		return nil, fmt.Errorf("get public key from contex failed")
@@ -70,43 +155,142 @@ func (bp *BatchProverTask) Collect(ctx *gin.Context) (*coordinatorType.ProverTas
 		return nil, fmt.Errorf("get prover name from contex failed")
 	}
 
+	var batchHashes []string
+	for len(batchHashes) < n {
+		batchHash, ok := bp.scheduler.Next()
+		if !ok {
+			break
+		}
+		batchHashes = append(batchHashes, batchHash)
+	}
+	if len(batchHashes) == 0 {
+		return nil, nil
+	}
+
+	assignedByHash := make(map[string]bool, len(batchHashes))
 	transErr := bp.db.Transaction(func(tx *gorm.DB) error {
-		// Update session proving status as assigned.
-		if err = bp.batchOrm.UpdateProvingStatus(ctx, batchTask.Hash, types.ProvingTaskAssigned, tx); err != nil {
-			return fmt.Errorf("failed to update task status, id:%s, error:%w", batchTask.Hash, err)
+		for _, batchHash := range batchHashes {
+			assigned, err := bp.assignBatchTask(ctx, tx, batchHash, publicKey.(string), proverName.(string))
+			if err != nil {
+				return err
+			}
+			assignedByHash[batchHash] = assigned
+		}
+		return nil
+	})
+	if transErr != nil {
+		for _, batchHash := range batchHashes {
+			bp.scheduler.Release(batchHash)
 		}
+		return nil, transErr
+	}
+	for _, batchHash := range batchHashes {
+		bp.scheduler.Confirm(batchHash)
+	}
 
-		proverTask := orm.ProverTask{
-			TaskID:          batchTask.Hash,
-			ProverPublicKey: publicKey.(string),
-			TaskType:        int16(message.ProofTypeBatch),
-			ProverName:      proverName.(string),
-			ProvingStatus:   int16(types.ProverAssigned),
-			FailureType:     int16(types.ProverTaskFailureTypeUndefined),
-			// here why need use UTC time. see scroll/common/databased/db.go
-			AssignedAt: utils.NowUTC(),
+	taskMsgs := make([]*coordinatorType.ProverTaskSchema, 0, len(batchHashes))
+	for _, batchHash := range batchHashes {
+		if !assignedByHash[batchHash] {
+			// attempts exhausted: already marked permanently failed above, skip it.
+			continue
+		}
+		bp.liveness.RecordAssignment(publicKey.(string))
+		taskMsg, err := bp.formatAndDispatch(ctx, batchHash, publicKey.(string))
+		if err != nil {
+			log.Error("failed to dispatch assigned batch task", "task_id", batchHash, "error", err)
+			continue
 		}
+		taskMsgs = append(taskMsgs, taskMsg)
+	}
 
-		// Store session info.
-		if err = bp.proverTaskOrm.SetProverTask(ctx, &proverTask, tx); err != nil {
-			return fmt.Errorf("db set session info fail, session id:%s, error:%w", proverTask.TaskID, err)
+	if len(taskMsgs) == 0 {
+		return nil, nil
+	}
+
+	log.Info("assigned batch proof generation sessions", "count", len(taskMsgs), "prover", proverName)
+	return taskMsgs, nil
+}
+
+// assignBatchTask assigns a single batch task to the given prover using tx.
+// assigned is false (with no error) when the task's attempt budget was
+// already exhausted, in which case the batch is permanently failed instead
+// of assigned. The caller controls the transaction boundary: Collect runs
+// this in its own single-task transaction, CollectBatch runs every task
+// pulled for a request through one shared transaction.
+func (bp *BatchProverTask) assignBatchTask(ctx *gin.Context, tx *gorm.DB, batchHash, publicKey, proverName string) (assigned bool, err error) {
+	if !bp.checkAttemptsExceeded(batchHash, message.ProofTypeBatch) {
+		if err := bp.batchOrm.UpdateProvingStatus(ctx, batchHash, types.ProvingTaskFailed, tx); err != nil {
+			return false, fmt.Errorf("failed to mark batch id:%s as permanently failed, error:%w", batchHash, err)
 		}
+		return false, nil
+	}
 
-		return nil
-	})
+	if err := bp.batchOrm.UpdateProvingStatus(ctx, batchHash, types.ProvingTaskAssigned, tx); err != nil {
+		return false, fmt.Errorf("failed to update task status, id:%s, error:%w", batchHash, err)
+	}
 
-	if transErr != nil {
-		return nil, transErr
+	proverTask := orm.ProverTask{
+		TaskID:          batchHash,
+		ProverPublicKey: publicKey,
+		TaskType:        int16(message.ProofTypeBatch),
+		ProverName:      proverName,
+		ProvingStatus:   int16(types.ProverAssigned),
+		FailureType:     int16(types.ProverTaskFailureTypeUndefined),
+		AssignedAt:      utils.NowUTC(),
 	}
+	if err := bp.proverTaskOrm.SetProverTask(ctx, &proverTask, tx); err != nil {
+		return false, fmt.Errorf("db set session info fail, session id:%s, error:%w", proverTask.TaskID, err)
+	}
+	return true, nil
+}
 
-	taskMsg, err := bp.formatProverTask(ctx, batchTask.Hash)
+// formatAndDispatch formats the prover task payload for an already-assigned
+// batchHash and, if a proof-server backend is configured, submits it for
+// external proving. A failure here is a dispatch-time failure, not a
+// proving failure, so it's compensated via compensateDispatchFailure rather
+// than left as ProvingTaskAssigned with nothing driving it forward.
+func (bp *BatchProverTask) formatAndDispatch(ctx *gin.Context, batchHash, publicKey string) (*coordinatorType.ProverTaskSchema, error) {
+	taskMsg, err := bp.formatProverTask(ctx, batchHash)
 	if err != nil {
-		return nil, fmt.Errorf("format prover failure, id:%s error:%w", batchTask.Hash, err)
+		bp.compensateDispatchFailure(ctx, batchHash, publicKey)
+		return nil, fmt.Errorf("format prover failure, id:%s error:%w", batchHash, err)
 	}
 
+	if bp.dispatcher == nil {
+		return taskMsg, nil
+	}
+
+	var detail message.BatchTaskDetail
+	if err := json.Unmarshal([]byte(taskMsg.ProofData), &detail); err != nil {
+		bp.compensateDispatchFailure(ctx, batchHash, publicKey)
+		return nil, fmt.Errorf("failed to unmarshal batch task detail, id:%s error:%w", batchHash, err)
+	}
+	if err := bp.dispatcher.Dispatch(ctx, batchHash, publicKey, &detail); err != nil {
+		bp.compensateDispatchFailure(ctx, batchHash, publicKey)
+		return nil, err
+	}
 	return taskMsg, nil
 }
 
+// compensateDispatchFailure undoes an assignment that was committed to the
+// DB but never made it to the prover (formatting or external dispatch
+// failed): it bumps the task's attempt count and restores the batch to
+// unassigned so the scheduler's next scan can hand it out again.
+func (bp *BatchProverTask) compensateDispatchFailure(ctx context.Context, batchHash, publicKey string) {
+	err := bp.db.Transaction(func(tx *gorm.DB) error {
+		if err := bp.proverTaskOrm.IncrementAttempts(ctx, batchHash, publicKey, tx); err != nil {
+			return err
+		}
+		if err := bp.proverTaskOrm.UpdateFailureType(ctx, batchHash, publicKey, types.ProverTaskFailureTypeTimeout, tx); err != nil {
+			return err
+		}
+		return bp.batchOrm.UpdateProvingStatus(ctx, batchHash, types.ProvingTaskUnassigned, tx)
+	})
+	if err != nil {
+		log.Error("failed to compensate dispatch failure", "task_id", batchHash, "error", err)
+	}
+}
+
 func (bp *BatchProverTask) formatProverTask(ctx context.Context, taskID string) (*coordinatorType.ProverTaskSchema, error) {
 	// get chunk from db
 	chunks, err := bp.chunkOrm.GetChunksByBatchHash(ctx, taskID)
@@ -0,0 +1,130 @@
+package provertask
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
+
+	"scroll-tech/coordinator/internal/orm"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// ProverLivenessTracker records a heartbeat timestamp per prover public key
+// and reclaims batch tasks from provers that have gone silent, so stuck
+// work doesn't need operator intervention to get reassigned.
+type ProverLivenessTracker struct {
+	batchOrm      *orm.Batch
+	proverTaskOrm *orm.ProverTask
+	db            *gorm.DB
+
+	timeout       time.Duration
+	scanInterval  time.Duration
+	mu            sync.RWMutex
+	lastHeartbeat map[string]time.Time
+}
+
+// NewProverLivenessTracker creates a liveness tracker for batch provers.
+func NewProverLivenessTracker(db *gorm.DB, timeout, scanInterval time.Duration) *ProverLivenessTracker {
+	return &ProverLivenessTracker{
+		batchOrm:      orm.NewBatch(db),
+		proverTaskOrm: orm.NewProverTask(db),
+		db:            db,
+		timeout:       timeout,
+		scanInterval:  scanInterval,
+		lastHeartbeat: make(map[string]time.Time),
+	}
+}
+
+// Heartbeat handles POST /prover/heartbeat, recording that the calling
+// prover is still alive.
+func (t *ProverLivenessTracker) Heartbeat(ctx *gin.Context) {
+	publicKey, publicKeyExist := ctx.Get(coordinatorType.PublicKey)
+	if !publicKeyExist {
+		ctx.Status(http.StatusUnauthorized)
+		return
+	}
+
+	t.recordHeartbeat(publicKey.(string))
+	ctx.Status(http.StatusOK)
+}
+
+func (t *ProverLivenessTracker) recordHeartbeat(pubkey string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastHeartbeat[pubkey] = utils.NowUTC()
+}
+
+// RecordAssignment seeds pubkey's heartbeat at task-assignment time, so a
+// freshly-assigned prover isn't indistinguishable from a dead one until its
+// own heartbeat loop fires for the first time. Collect and CollectBatch call
+// this right after assigning a task to pubkey.
+func (t *ProverLivenessTracker) RecordAssignment(pubkey string) {
+	t.recordHeartbeat(pubkey)
+}
+
+// IsAlive reports whether pubkey has heartbeated within the configured
+// ProverLivenessTimeout.
+func (t *ProverLivenessTracker) IsAlive(pubkey string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	last, ok := t.lastHeartbeat[pubkey]
+	if !ok {
+		return false
+	}
+	return utils.NowUTC().Sub(last) < t.timeout
+}
+
+// Start launches the background loop that reclaims tasks owned by provers
+// that have stopped heartbeating. It returns once ctx is cancelled.
+func (t *ProverLivenessTracker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(t.scanInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.reclaimDeadProverTasks(ctx)
+			}
+		}
+	}()
+}
+
+func (t *ProverLivenessTracker) reclaimDeadProverTasks(ctx context.Context) {
+	assigned, err := t.proverTaskOrm.GetTasksByProvingStatus(ctx, types.ProverAssigned, message.ProofTypeBatch)
+	if err != nil {
+		log.Error("liveness tracker failed to list assigned prover tasks", "error", err)
+		return
+	}
+
+	for _, task := range assigned {
+		if t.IsAlive(task.ProverPublicKey) {
+			continue
+		}
+
+		log.Warn("reclaiming batch task from unresponsive prover", "task_id", task.TaskID, "prover", task.ProverPublicKey)
+
+		transErr := t.db.Transaction(func(tx *gorm.DB) error {
+			if err := t.proverTaskOrm.IncrementAttempts(ctx, task.TaskID, task.ProverPublicKey, tx); err != nil {
+				return err
+			}
+			if err := t.proverTaskOrm.UpdateFailureType(ctx, task.TaskID, task.ProverPublicKey, types.ProverTaskFailureTypeTimeout, tx); err != nil {
+				return err
+			}
+			return t.batchOrm.UpdateProvingStatus(ctx, task.TaskID, types.ProvingTaskUnassigned, tx)
+		})
+		if transErr != nil {
+			log.Error("failed to reclaim batch task from unresponsive prover", "task_id", task.TaskID, "error", transErr)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package provertask
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLivenessTracker builds a ProverLivenessTracker with no DB access,
+// exercising only the in-memory heartbeat bookkeeping these tests cover.
+func newTestLivenessTracker(timeout time.Duration) *ProverLivenessTracker {
+	return &ProverLivenessTracker{
+		timeout:       timeout,
+		lastHeartbeat: make(map[string]time.Time),
+	}
+}
+
+func TestProverLivenessTrackerUnknownProverIsDead(t *testing.T) {
+	tr := newTestLivenessTracker(time.Minute)
+	assert.False(t, tr.IsAlive("unknown-pubkey"))
+}
+
+func TestProverLivenessTrackerRecordAssignmentSeedsHeartbeat(t *testing.T) {
+	tr := newTestLivenessTracker(time.Minute)
+
+	// Before any assignment or heartbeat, the prover is considered dead.
+	assert.False(t, tr.IsAlive("pubkey-a"))
+
+	// Assigning a task seeds the heartbeat so the prover isn't reclaimed
+	// from under itself before its own heartbeat loop has a chance to run.
+	tr.RecordAssignment("pubkey-a")
+	assert.True(t, tr.IsAlive("pubkey-a"))
+}
+
+func TestProverLivenessTrackerExpiresAfterTimeout(t *testing.T) {
+	tr := newTestLivenessTracker(10 * time.Millisecond)
+
+	tr.RecordAssignment("pubkey-a")
+	assert.True(t, tr.IsAlive("pubkey-a"))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, tr.IsAlive("pubkey-a"))
+}
@@ -0,0 +1,267 @@
+package provertask
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/orm"
+)
+
+// ProofBackend dispatches a batch task for proving and reports back its
+// result. PullBackend keeps the existing pull-based prover model working
+// unchanged; ProofServerBackend lets the coordinator front a stateless
+// external proof server instead.
+type ProofBackend interface {
+	// Submit hands the task detail to the backend and returns an opaque job
+	// id the backend can later be polled with.
+	Submit(ctx context.Context, taskID string, detail *message.BatchTaskDetail) (jobID string, err error)
+	// Poll returns the backend's current status for jobID, and the proof
+	// once status reports it as finished.
+	Poll(ctx context.Context, jobID string) (status string, proof *message.BatchProof, err error)
+}
+
+// Proof-server job statuses.
+const (
+	ProofServerStatusPending  = "pending"
+	ProofServerStatusRunning  = "running"
+	ProofServerStatusFinished = "finished"
+	ProofServerStatusFailed   = "failed"
+)
+
+// PullBackend is the no-op backend used by the existing pull-based prover
+// model: provers call Collect/CollectBatch themselves, so there is nothing
+// for the coordinator to submit or poll.
+type PullBackend struct{}
+
+// NewPullBackend returns the default pull-based backend.
+func NewPullBackend() *PullBackend { return &PullBackend{} }
+
+// Submit is a no-op for the pull backend; the task stays in the DB until a
+// prover pulls it via Collect.
+func (b *PullBackend) Submit(_ context.Context, taskID string, _ *message.BatchTaskDetail) (string, error) {
+	return taskID, nil
+}
+
+// Poll always reports pending, since pull-backend tasks are resolved via the
+// normal SubmitProof endpoint rather than polling.
+func (b *PullBackend) Poll(_ context.Context, _ string) (string, *message.BatchProof, error) {
+	return ProofServerStatusPending, nil, nil
+}
+
+// ProofServerBackend submits batch tasks to an external proof server over
+// HTTP and polls it for results.
+type ProofServerBackend struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewProofServerBackend creates a backend that talks to the proof server
+// listening at endpoint.
+func NewProofServerBackend(endpoint string) *ProofServerBackend {
+	return &ProofServerBackend{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type proofServerSubmitRequest struct {
+	TaskID string                   `json:"task_id"`
+	Detail *message.BatchTaskDetail `json:"detail"`
+}
+
+type proofServerSubmitResponse struct {
+	JobID string `json:"job_id"`
+}
+
+// Submit posts the task detail to the proof server's submit endpoint.
+func (b *ProofServerBackend) Submit(ctx context.Context, taskID string, detail *message.BatchTaskDetail) (string, error) {
+	body, err := json.Marshal(proofServerSubmitRequest{TaskID: taskID, Detail: detail})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal proof server submit request, task id:%s err:%w", taskID, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint+"/submit", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build proof server submit request, task id:%s err:%w", taskID, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit task to proof server, task id:%s err:%w", taskID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("proof server submit returned status %d, task id:%s", resp.StatusCode, taskID)
+	}
+
+	var submitResp proofServerSubmitResponse
+	if err := json.NewDecoder(resp.Body).Decode(&submitResp); err != nil {
+		return "", fmt.Errorf("failed to decode proof server submit response, task id:%s err:%w", taskID, err)
+	}
+	return submitResp.JobID, nil
+}
+
+type proofServerPollResponse struct {
+	Status string              `json:"status"`
+	Proof  *message.BatchProof `json:"proof,omitempty"`
+}
+
+// Poll asks the proof server for the status of jobID.
+func (b *ProofServerBackend) Poll(ctx context.Context, jobID string) (string, *message.BatchProof, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.endpoint+"/poll?job_id="+jobID, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build proof server poll request, job id:%s err:%w", jobID, err)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to poll proof server, job id:%s err:%w", jobID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("proof server poll returned status %d, job id:%s", resp.StatusCode, jobID)
+	}
+
+	var pollResp proofServerPollResponse
+	if err := json.NewDecoder(resp.Body).Decode(&pollResp); err != nil {
+		return "", nil, fmt.Errorf("failed to decode proof server poll response, job id:%s err:%w", jobID, err)
+	}
+	return pollResp.Status, pollResp.Proof, nil
+}
+
+// dispatchedJob tracks the proof-server job backing a dispatched task, plus
+// the prover public key the task was assigned to so a failed job can put the
+// batch back up for reassignment.
+type dispatchedJob struct {
+	jobID           string
+	proverPublicKey string
+}
+
+// ProofServerDispatcher drives ProofServerBackend tasks to completion,
+// polling the backend on an interval and writing finished proofs back
+// through batchOrm.UpdateProofByHash. jobsByTaskID is written from Dispatch
+// (the Collect HTTP-handler goroutine) and read/deleted from
+// pollOutstandingJobs (the ticker goroutine), so access is guarded by mu.
+type ProofServerDispatcher struct {
+	backend       ProofBackend
+	batchOrm      *orm.Batch
+	proverTaskOrm *orm.ProverTask
+	db            *gorm.DB
+	pollInterval  time.Duration
+
+	mu           sync.Mutex
+	jobsByTaskID map[string]dispatchedJob
+}
+
+// NewProofServerDispatcher creates a dispatcher driving backend on pollInterval.
+func NewProofServerDispatcher(backend ProofBackend, db *gorm.DB, pollInterval time.Duration) *ProofServerDispatcher {
+	return &ProofServerDispatcher{
+		backend:       backend,
+		batchOrm:      orm.NewBatch(db),
+		proverTaskOrm: orm.NewProverTask(db),
+		db:            db,
+		pollInterval:  pollInterval,
+		jobsByTaskID:  make(map[string]dispatchedJob),
+	}
+}
+
+// Dispatch submits a task to the backend and starts tracking it for polling.
+func (d *ProofServerDispatcher) Dispatch(ctx context.Context, taskID, proverPublicKey string, detail *message.BatchTaskDetail) error {
+	jobID, err := d.backend.Submit(ctx, taskID, detail)
+	if err != nil {
+		return fmt.Errorf("failed to dispatch task to proof server, task id:%s err:%w", taskID, err)
+	}
+	d.mu.Lock()
+	d.jobsByTaskID[taskID] = dispatchedJob{jobID: jobID, proverPublicKey: proverPublicKey}
+	d.mu.Unlock()
+	return nil
+}
+
+// Start launches the background polling loop. It returns once ctx is cancelled.
+func (d *ProofServerDispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.pollOutstandingJobs(ctx)
+			}
+		}
+	}()
+}
+
+func (d *ProofServerDispatcher) pollOutstandingJobs(ctx context.Context) {
+	d.mu.Lock()
+	jobs := make(map[string]dispatchedJob, len(d.jobsByTaskID))
+	for taskID, job := range d.jobsByTaskID {
+		jobs[taskID] = job
+	}
+	d.mu.Unlock()
+
+	for taskID, job := range jobs {
+		status, proof, err := d.backend.Poll(ctx, job.jobID)
+		if err != nil {
+			log.Error("failed to poll proof server job", "task_id", taskID, "job_id", job.jobID, "error", err)
+			continue
+		}
+
+		switch status {
+		case ProofServerStatusFinished:
+			proofBytes, err := json.Marshal(proof)
+			if err != nil {
+				log.Error("failed to marshal proof server result", "task_id", taskID, "error", err)
+				continue
+			}
+			if err := d.batchOrm.UpdateProofByHash(ctx, taskID, proofBytes); err != nil {
+				log.Error("failed to write back proof server result", "task_id", taskID, "error", err)
+				continue
+			}
+			d.forget(taskID)
+		case ProofServerStatusFailed:
+			log.Error("proof server job failed", "task_id", taskID, "job_id", job.jobID)
+			if err := d.reassign(ctx, taskID, job.proverPublicKey); err != nil {
+				log.Error("failed to reassign batch after proof server job failure", "task_id", taskID, "error", err)
+				continue
+			}
+			d.forget(taskID)
+		}
+	}
+}
+
+// reassign bumps the task's attempt count and puts the batch back up for
+// assignment after its proof-server job failed, so it doesn't stay stuck in
+// ProvingTaskAssigned forever.
+func (d *ProofServerDispatcher) reassign(ctx context.Context, taskID, proverPublicKey string) error {
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		if err := d.proverTaskOrm.IncrementAttempts(ctx, taskID, proverPublicKey, tx); err != nil {
+			return err
+		}
+		if err := d.proverTaskOrm.UpdateFailureType(ctx, taskID, proverPublicKey, types.ProverTaskFailureTypeTimeout, tx); err != nil {
+			return err
+		}
+		return d.batchOrm.UpdateProvingStatus(ctx, taskID, types.ProvingTaskUnassigned, tx)
+	})
+}
+
+func (d *ProofServerDispatcher) forget(taskID string) {
+	d.mu.Lock()
+	delete(d.jobsByTaskID, taskID)
+	d.mu.Unlock()
+}
@@ -0,0 +1,222 @@
+package provertask
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
+
+	"scroll-tech/coordinator/internal/orm"
+)
+
+// schedulerTask is a single unit of work handed out by the TaskScheduler.
+// Older tasks and tasks that have already failed once are given a lower
+// (more urgent) priority so Collect hands them out first.
+type schedulerTask struct {
+	batchHash string
+	attempts  int16
+	queuedAt  time.Time
+	index     int
+}
+
+// taskPriorityQueue orders schedulerTask entries oldest/most-attempted first.
+type taskPriorityQueue []*schedulerTask
+
+func (q taskPriorityQueue) Len() int { return len(q) }
+
+func (q taskPriorityQueue) Less(i, j int) bool {
+	if q[i].attempts != q[j].attempts {
+		return q[i].attempts > q[j].attempts
+	}
+	return q[i].queuedAt.Before(q[j].queuedAt)
+}
+
+func (q taskPriorityQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *taskPriorityQueue) Push(x interface{}) {
+	task := x.(*schedulerTask)
+	task.index = len(*q)
+	*q = append(*q, task)
+}
+
+func (q *taskPriorityQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	task.index = -1
+	*q = old[:n-1]
+	return task
+}
+
+// TaskScheduler periodically scans the DB for unassigned batches and
+// assigned-but-timed-out tasks, and keeps them in an in-memory priority
+// queue so Collect no longer has to hit the DB on every HTTP request.
+type TaskScheduler struct {
+	cfg           *schedulerConfig
+	batchOrm      *orm.Batch
+	proverTaskOrm *orm.ProverTask
+	db            *gorm.DB
+
+	mu    sync.Mutex
+	queue taskPriorityQueue
+	known map[string]bool
+}
+
+// schedulerConfig holds the knobs the scheduler needs from the coordinator config.
+type schedulerConfig struct {
+	ScanInterval  time.Duration
+	ProofDeadline time.Duration
+	MaxAttempts   uint8
+	QueueDepth    int
+}
+
+// NewTaskScheduler creates a scheduler for batch proving tasks.
+func NewTaskScheduler(db *gorm.DB, scanInterval, proofDeadline time.Duration, maxAttempts uint8, queueDepth int) *TaskScheduler {
+	s := &TaskScheduler{
+		cfg: &schedulerConfig{
+			ScanInterval:  scanInterval,
+			ProofDeadline: proofDeadline,
+			MaxAttempts:   maxAttempts,
+			QueueDepth:    queueDepth,
+		},
+		batchOrm:      orm.NewBatch(db),
+		proverTaskOrm: orm.NewProverTask(db),
+		db:            db,
+		known:         make(map[string]bool),
+	}
+	heap.Init(&s.queue)
+	return s
+}
+
+// Start launches the background scan loop. It returns once ctx is cancelled.
+func (s *TaskScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.ScanInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.scanAndReassign(ctx)
+			}
+		}
+	}()
+}
+
+// scanAndReassign scans the DB for unassigned batches and timed-out assigned
+// tasks, classifies them, and enqueues them so older/previously-failed tasks
+// are handed out first.
+func (s *TaskScheduler) scanAndReassign(ctx context.Context) {
+	unassigned, err := s.batchOrm.GetUnassignedBatches(ctx, s.cfg.QueueDepth)
+	if err != nil {
+		log.Error("scheduler failed to get unassigned batches", "error", err)
+	}
+	for _, batch := range unassigned {
+		s.enqueue(batch.Hash, 0)
+	}
+
+	timedOut, err := s.proverTaskOrm.GetAssignedTasksOlderThan(ctx, message.ProofTypeBatch, s.cfg.ProofDeadline)
+	if err != nil {
+		log.Error("scheduler failed to get timed-out prover tasks", "error", err)
+		return
+	}
+
+	for _, task := range timedOut {
+		if s.attemptsExhausted(ctx, task) {
+			continue
+		}
+		if err := s.reassign(ctx, task); err != nil {
+			log.Error("scheduler failed to reassign timed-out task", "task_id", task.TaskID, "error", err)
+			continue
+		}
+		s.enqueue(task.TaskID, task.Attempts)
+	}
+}
+
+// attemptsExhausted permanently fails the batch once its prover task has
+// exhausted the configured attempt budget. Note the polarity: unlike
+// BaseCollector.checkAttemptsExceeded (true means "OK to proceed"), this
+// returns true when the budget IS exhausted, which is why it has a
+// different name.
+func (s *TaskScheduler) attemptsExhausted(ctx context.Context, task orm.ProverTask) bool {
+	if uint8(task.Attempts) < s.cfg.MaxAttempts {
+		return false
+	}
+	if err := s.batchOrm.UpdateProvingStatus(ctx, task.TaskID, types.ProvingTaskFailed, s.db); err != nil {
+		log.Error("failed to mark batch as permanently failed", "task_id", task.TaskID, "error", err)
+	}
+	return true
+}
+
+// reassign bumps the Attempts counter on the prover task and restores the
+// batch's proving status so another prover can pick it up.
+func (s *TaskScheduler) reassign(ctx context.Context, task orm.ProverTask) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.proverTaskOrm.IncrementAttempts(ctx, task.TaskID, task.ProverPublicKey, tx); err != nil {
+			return err
+		}
+		if err := s.proverTaskOrm.UpdateFailureType(ctx, task.TaskID, task.ProverPublicKey, types.ProverTaskFailureTypeTimeout, tx); err != nil {
+			return err
+		}
+		return s.batchOrm.UpdateProvingStatus(ctx, task.TaskID, types.ProvingTaskUnassigned, tx)
+	})
+}
+
+func (s *TaskScheduler) enqueue(batchHash string, attempts int16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.known[batchHash] {
+		return
+	}
+	s.known[batchHash] = true
+	heap.Push(&s.queue, &schedulerTask{
+		batchHash: batchHash,
+		attempts:  attempts,
+		queuedAt:  utils.NowUTC(),
+	})
+}
+
+// Next pops the highest priority task off the queue, or returns false if the
+// queue is currently empty. The batch hash stays marked as known until the
+// caller reports back via Confirm or Release, so a scanAndReassign tick that
+// runs before the caller's assignment transaction commits doesn't observe
+// the batch as still-unassigned in the DB and hand it out a second time.
+func (s *TaskScheduler) Next() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queue.Len() == 0 {
+		return "", false
+	}
+	task := heap.Pop(&s.queue).(*schedulerTask)
+	return task.batchHash, true
+}
+
+// Confirm reports that batchHash returned by Next was successfully assigned,
+// so the scheduler can stop suppressing re-enqueues for it.
+func (s *TaskScheduler) Confirm(batchHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.known, batchHash)
+}
+
+// Release reports that the assignment for batchHash returned by Next failed,
+// so the scheduler should stop suppressing re-enqueues and let the next scan
+// pick it back up.
+func (s *TaskScheduler) Release(batchHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.known, batchHash)
+}
@@ -0,0 +1,77 @@
+package provertask
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestScheduler builds a TaskScheduler with no DB access, exercising only
+// the in-memory priority queue (enqueue/Next/Confirm/Release), which is all
+// these tests touch.
+func newTestScheduler() *TaskScheduler {
+	s := &TaskScheduler{
+		cfg:   &schedulerConfig{},
+		known: make(map[string]bool),
+	}
+	return s
+}
+
+func TestTaskSchedulerPriorityOrder(t *testing.T) {
+	s := newTestScheduler()
+
+	s.enqueue("oldest", 0)
+	time.Sleep(time.Millisecond)
+	s.enqueue("newer", 0)
+	s.enqueue("most-attempted", 3)
+
+	// Higher attempts are handed out first regardless of age.
+	hash, ok := s.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "most-attempted", hash)
+
+	// Among equal attempts, the older (first-enqueued) task comes next.
+	hash, ok = s.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "oldest", hash)
+
+	hash, ok = s.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "newer", hash)
+
+	_, ok = s.Next()
+	assert.False(t, ok)
+}
+
+func TestTaskSchedulerEnqueueDedup(t *testing.T) {
+	s := newTestScheduler()
+
+	s.enqueue("batch-a", 0)
+	s.enqueue("batch-a", 0)
+	assert.Equal(t, 1, s.queue.Len())
+}
+
+func TestTaskSchedulerConfirmReleaseAllowReenqueue(t *testing.T) {
+	s := newTestScheduler()
+
+	s.enqueue("batch-a", 0)
+	hash, ok := s.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "batch-a", hash)
+
+	// While dispatched but not yet confirmed/released, the hash stays known
+	// so a concurrent scan can't re-enqueue it.
+	s.enqueue("batch-a", 0)
+	assert.Equal(t, 0, s.queue.Len())
+
+	s.Release(hash)
+	s.enqueue("batch-a", 0)
+	assert.Equal(t, 1, s.queue.Len())
+
+	hash, ok = s.Next()
+	assert.True(t, ok)
+	s.Confirm(hash)
+	s.enqueue("batch-a", 0)
+	assert.Equal(t, 1, s.queue.Len())
+}